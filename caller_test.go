@@ -0,0 +1,60 @@
+package gqlerr
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNew_CallerPointsAtDirectCaller(t *testing.T) {
+	e := New(context.Background(), Internal(context.Background(), "").Code(), "some error")
+	if !strings.Contains(e.caller, "caller_test.go") {
+		t.Errorf("e.caller = %q, want it to contain this test file", e.caller)
+	}
+}
+
+func TestHelpers_CallerPointsAtRealCaller(t *testing.T) {
+	// Internal is itself a helper wrapping New, so its recorded caller should
+	// point here, not at the line inside Internal.
+	e := Internal(context.Background(), "some error")
+	if !strings.Contains(e.caller, "caller_test.go") {
+		t.Errorf("e.caller = %q, want it to contain this test file, not gqlerr.go", e.caller)
+	}
+}
+
+func wrappingHelper(ctx context.Context, msg string) *Error {
+	return New(ctx, Internal(ctx, "").Code(), msg).WithSkip(1)
+}
+
+func TestWithSkip(t *testing.T) {
+	e := wrappingHelper(context.Background(), "some error")
+	if !strings.Contains(e.caller, "caller_test.go") {
+		t.Errorf("e.caller = %q, want it to contain this test file, not wrappingHelper's", e.caller)
+	}
+}
+
+func TestNewPresenter_WithStackCapture_Disabled(t *testing.T) {
+	core, logs := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	present := NewPresenter(WithLogger(logger), WithStackCapture(false))
+	present(context.Background(), Internal(context.Background(), "boom"))
+
+	gotLogs := logs.AllUntimed()
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(gotLogs))
+	}
+	for _, f := range gotLogs[0].Context {
+		if f.Key == "gqlerr_stacktrace" {
+			t.Error("got a stacktrace field, want none since stack capture was disabled")
+		}
+		if f.Key == "gqlerr_caller" && f.String == "" {
+			t.Error("expected a non-empty caller field even with stack capture disabled")
+		}
+	}
+}
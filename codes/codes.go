@@ -4,22 +4,116 @@
 // [1] https://pkg.go.dev/google.golang.org/grpc@v1.44.0/codes#Code
 package codes
 
-type Code string
+import "fmt"
+
+// Category buckets a Code by the general kind of failure it represents, so
+// that clients and on-call engineers can reason about an error even across
+// scopes they don't otherwise recognize.
+type Category uint16
 
 const (
+	CategoryUnspecified Category = iota
+	// CategoryInput covers errors caused by a malformed or otherwise invalid
+	// client request.
+	CategoryInput
+	// CategoryDB covers errors originating from a datastore.
+	CategoryDB
+	// CategoryResource covers errors about the existence/state of a
+	// requested resource, e.g. not found or already exists.
+	CategoryResource
+	// CategoryAuth covers authentication and authorization failures.
+	CategoryAuth
+	// CategorySystem covers internal/backend failures that aren't
+	// attributable to the client or a specific dependency.
+	CategorySystem
+	// CategoryPubsub covers errors from publishing/subscribing to a
+	// messaging system.
+	CategoryPubsub
+	// CategoryGRPC covers errors surfaced from a downstream gRPC call.
+	CategoryGRPC
+)
+
+// Scope identifies the bounded context/microservice that registered a Code,
+// so that two services can reuse the same category/detail pair without their
+// FullCodes colliding. Services that don't need to distinguish themselves
+// from others can use DefaultScope.
+type Scope uint16
+
+// DefaultScope is the scope used for the pre-registered, library-wide Codes
+// below. Single-service deployments that don't need to partition their codes
+// by scope can keep using it for their own Codes too.
+const DefaultScope Scope = 0
+
+// Code is a hierarchical error identifier, structured to be stable across
+// releases and cheap to switch on: a service Scope, a Category of failure
+// within that service, and a detail code distinguishing errors within the
+// category. Combined, they form a fixed-width numeric FullCode
+// (scope*10000 + category*100 + detail), while String() still returns a
+// human-readable name suitable for logs and for serializing into a GraphQL
+// response.
+type Code struct {
+	scope    Scope
+	category Category
+	detail   uint16
+	name     string
+}
+
+// NewCode creates a new Code from the given scope, category, and detail.
+// Use WithName to attach the human-readable name that String() and the
+// GraphQL "code" extension will use.
+//
+// category and detail must each fit in two digits (0-99), since FullCode
+// packs them into fixed-width fields; NewCode panics if either overflows,
+// since a silent overflow would let two distinct Codes collide on the same
+// FullCode.
+func NewCode(scope Scope, category Category, detail uint16) Code {
+	if category > 99 {
+		panic(fmt.Sprintf("codes: category %d overflows FullCode's 2-digit category field (must be 0-99)", category))
+	}
+	if detail > 99 {
+		panic(fmt.Sprintf("codes: detail %d overflows FullCode's 2-digit detail field (must be 0-99)", detail))
+	}
+	return Code{scope: scope, category: category, detail: detail}
+}
+
+// WithName attaches a human-readable name to the Code, returning the Code
+// for chaining. It's serialized as the GraphQL "code" extension and should
+// stay stable once clients depend on it, much like the detail/category/scope
+// that make up the FullCode.
+func (c Code) WithName(name string) Code {
+	c.name = name
+	return c
+}
+
+// FullCode combines the Code's scope, category, and detail into a single
+// fixed-width numeric identifier, so that clients can switch on a stable
+// number instead of parsing/prefix-matching a string.
+func (c Code) FullCode() uint32 {
+	return uint32(c.scope)*10000 + uint32(c.category)*100 + uint32(c.detail)
+}
+
+// String returns the Code's human-readable name, as set by WithName.
+func (c Code) String() string {
+	return c.name
+}
+
+// The following are pre-registered under DefaultScope so that existing
+// callers that depend on these exact string codes are unaffected by the
+// addition of FullCode.
+var (
 	// InvalidArgument indicates client specified an invalid argument.
 	// Note that this differs from FailedPrecondition. It indicates arguments
 	// that are problematic regardless of the state of the system
 	// (e.g., a malformed file name).
-	InvalidArgument = Code("invalid_argument")
+	InvalidArgument = NewCode(DefaultScope, CategoryInput, 1).WithName("invalid_argument")
 
 	// NotFound means some requested entity (e.g., file or directory) was
 	// not found.
-	NotFound = Code("not_found")
+	NotFound = NewCode(DefaultScope, CategoryResource, 1).WithName("not_found")
 
 	// AlreadyExists means an attempt to create an entity failed because one
 	// already exists.
-	AlreadyExists = Code("already_exists")
+	AlreadyExists = NewCode(DefaultScope, CategoryResource, 2).WithName("already_exists")
 
 	// PermissionDenied indicates the caller does not have permission to
 	// execute the specified operation. It must not be used for rejections
@@ -27,28 +121,28 @@ const (
 	// instead for those errors). It must not be
 	// used if the caller cannot be identified (use Unauthenticated
 	// instead for those errors).
-	PermissionDenied = Code("permission_denied")
+	PermissionDenied = NewCode(DefaultScope, CategoryAuth, 1).WithName("permission_denied")
 
 	// ResourceExhausted indicates some resource has been exhausted, perhaps
 	// a per-user quota, or perhaps the entire file system is out of space.
-	ResourceExhausted = Code("resource_exhausted")
+	ResourceExhausted = NewCode(DefaultScope, CategoryResource, 3).WithName("resource_exhausted")
 
 	// FailedPrecondition indicates operation was rejected because the
 	// system is not in a state required for the operation's execution.
 	// For example, directory to be deleted may be non-empty, an rmdir
 	// operation is applied to a non-directory, etc.
-	FailedPrecondition = Code("failed_precondition")
+	FailedPrecondition = NewCode(DefaultScope, CategorySystem, 1).WithName("failed_precondition")
 
 	// Unimplemented indicates operation is not implemented or not
 	// supported/enabled in this service.
-	Unimplemented = Code("unimplemented")
+	Unimplemented = NewCode(DefaultScope, CategorySystem, 2).WithName("unimplemented")
 
 	// Internal errors. Means some invariants expected by underlying
 	// system has been broken. If you see one of these errors,
 	// something is very broken.
-	Internal = Code("internal")
+	Internal = NewCode(DefaultScope, CategorySystem, 3).WithName("internal")
 
 	// Unauthenticated indicates the request does not have valid
 	// authentication credentials for the operation.
-	Unauthenticated = Code("unauthenticated")
+	Unauthenticated = NewCode(DefaultScope, CategoryAuth, 2).WithName("unauthenticated")
 )
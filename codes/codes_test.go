@@ -0,0 +1,73 @@
+package codes
+
+import "testing"
+
+func TestCode_FullCode(t *testing.T) {
+	tests := []struct {
+		desc string
+		in   Code
+		want uint32
+	}{
+		{
+			desc: "default scope",
+			in:   NewCode(DefaultScope, CategoryInput, 1),
+			want: 101,
+		},
+		{
+			desc: "non-default scope",
+			in:   NewCode(Scope(7), CategoryDB, 42),
+			want: 70242,
+		},
+		{
+			desc: "pre-registered constant",
+			in:   Internal,
+			want: 503,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := test.in.FullCode(); got != test.want {
+				t.Errorf("FullCode() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCode_WithName(t *testing.T) {
+	c := NewCode(DefaultScope, CategoryPubsub, 1).WithName("message_too_large")
+	if got, want := c.String(), "message_too_large"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestNewCode_OverflowPanics(t *testing.T) {
+	tests := []struct {
+		desc     string
+		category Category
+		detail   uint16
+	}{
+		{desc: "category overflows", category: 100, detail: 1},
+		{desc: "detail overflows", category: CategoryDB, detail: 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("NewCode did not panic on overflow")
+				}
+			}()
+			NewCode(DefaultScope, test.category, test.detail)
+		})
+	}
+}
+
+func TestNewCode_BoundaryValuesDoNotCollide(t *testing.T) {
+	a := NewCode(DefaultScope, 1, 99)
+	b := NewCode(DefaultScope, 2, 0)
+
+	if a.FullCode() == b.FullCode() {
+		t.Errorf("distinct codes at the category/detail boundary collided: %d == %d", a.FullCode(), b.FullCode())
+	}
+}
@@ -6,8 +6,8 @@ package gqlerr
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"runtime"
 	"runtime/debug"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -15,7 +15,6 @@ import (
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 )
 
 // ErrorID represents a type of error specific to the domain of the caller of
@@ -81,6 +80,14 @@ type Error struct {
 	// to be set.
 	fields  []zap.Field
 	errorID ErrorID
+
+	// cause is the underlying error that led to this one, if any. It's set
+	// via Wrap, and is surfaced to errors.Is/errors.As through Unwrap.
+	cause error
+
+	// caller is the "file:line" of whoever constructed this error, captured
+	// by New and corrected for wrapping via WithSkip.
+	caller string
 }
 
 func (e *Error) Error() string {
@@ -88,27 +95,44 @@ func (e *Error) Error() string {
 		return ""
 	}
 
-	err := e.err()
-	if err == nil {
+	if e.cause == nil {
 		// We only write the code and message for now, the actual logger should log
 		// the fields.
 		return fmt.Sprintf("[%q] %s", e.code, e.msg)
 	}
-	return fmt.Sprintf("[%q] %s: %v", e.code, e.msg, err)
-}
-
-func (e *Error) err() error {
-	for _, f := range e.fields {
-		if f.Key != "error" || f.Type != zapcore.ErrorType {
-			continue
-		}
-		errVal, ok := f.Interface.(error)
-		if !ok {
-			continue
-		}
-		return errVal
+	return fmt.Sprintf("[%q] %s: %v", e.code, e.msg, e.cause)
+}
+
+// Wrap sets the underlying cause of the error, and returns the error for
+// chaining purposes. The cause is logged alongside the error's message, and
+// is reachable by callers via errors.Is/errors.As on the returned *Error,
+// since it's exposed through Unwrap.
+func (e *Error) Wrap(err error) *Error {
+	e.cause = err
+	return e
+}
+
+// Unwrap returns the error's cause, if one was set via Wrap, so that
+// errors.Is and errors.As can traverse into it.
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
 	}
-	return nil
+	return e.cause
+}
+
+// Is reports whether target is a *Error with the same code as e, so that
+// errors.Is(err, gqlerr.NotFound(ctx, "")) can be used to check the kind of
+// error that occurred without comparing messages or pointers.
+func (e *Error) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.code == t.code
 }
 
 func (e *Error) toGQLError() *gqlerror.Error {
@@ -125,7 +149,8 @@ func (e *Error) toGQLError() *gqlerror.Error {
 
 func (e *Error) extensions() map[string]interface{} {
 	ext := map[string]interface{}{
-		"code": string(e.code),
+		"code":      e.code.String(),
+		"full_code": e.code.FullCode(),
 	}
 
 	if e.errorID != "" {
@@ -161,6 +186,34 @@ func (e *Error) clientMessage() string {
 	return defaultMessageForCode[e.code]
 }
 
+// Code returns the error's codes.Code.
+func (e *Error) Code() codes.Code {
+	return e.code
+}
+
+// ClientMessage returns the message that'll be shown to clients, following
+// the same defaulting rules as the GraphQL response: the message set via
+// WithMessage if there is one, otherwise the default for the error's code.
+func (e *Error) ClientMessage() string {
+	return e.clientMessage()
+}
+
+// Path returns the GraphQL resolver path the error occurred at, as captured
+// from the context passed to New.
+func (e *Error) Path() ast.Path {
+	return e.path
+}
+
+// ErrorID returns the error's client-facing ErrorID, as set via WithErrorID.
+func (e *Error) ErrorID() ErrorID {
+	return e.errorID
+}
+
+// Fields returns the structured logging fields attached to the error.
+func (e *Error) Fields() []zap.Field {
+	return e.fields
+}
+
 // WithMessage adds an error intended for clients to see, and returns the error
 // for chaining purposes. It'll appear in the GraphQL response "errors" field,
 // see: https://spec.graphql.org/October2021/#sec-Errors
@@ -210,6 +263,30 @@ func (e *Error) AtPanic() *Error {
 	return e
 }
 
+// WithSkip corrects the error's captured caller frame for an extra n levels
+// of wrapping, so a helper built on top of New can make the recorded caller
+// point at its own caller rather than at itself, e.g.:
+//
+//	func MyHelper(ctx context.Context, msg string) *Error {
+//		return New(ctx, codes.Internal, msg).WithSkip(1)
+//	}
+func (e *Error) WithSkip(n int) *Error {
+	e.caller = caller(n)
+	return e
+}
+
+// caller returns the "file:line" of the function that's skip levels above
+// whoever called the function calling caller, for attaching to an *Error at
+// construction time. skip=0 means the direct caller of the function calling
+// caller, e.g. whoever called New.
+func caller(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 2)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
 // New returns an initialize error with the given code. The message and fields
 // are used for logging, and won't be visible to clients. For setting client-
 // visible response parameters, see WithErrorID and WithMessage
@@ -219,73 +296,65 @@ func New(ctx context.Context, code codes.Code, msg string, fields ...zap.Field)
 		msg:    msg,
 		path:   graphql.GetPath(ctx),
 		fields: fields,
+		caller: caller(0),
 	}
 }
 
 func Internal(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.Internal, msg, fields...)
+	return New(ctx, codes.Internal, msg, fields...).WithSkip(1)
 }
 
 func InvalidArgument(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.InvalidArgument, msg, fields...)
+	return New(ctx, codes.InvalidArgument, msg, fields...).WithSkip(1)
 }
 
 func NotFound(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.NotFound, msg, fields...)
+	return New(ctx, codes.NotFound, msg, fields...).WithSkip(1)
 }
 
 func AlreadyExists(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.AlreadyExists, msg, fields...)
+	return New(ctx, codes.AlreadyExists, msg, fields...).WithSkip(1)
 }
 
 func PermissionDenied(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.PermissionDenied, msg, fields...)
+	return New(ctx, codes.PermissionDenied, msg, fields...).WithSkip(1)
 }
 
 func ResourceExhausted(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.ResourceExhausted, msg, fields...)
+	return New(ctx, codes.ResourceExhausted, msg, fields...).WithSkip(1)
 }
 
 func FailedPrecondition(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.FailedPrecondition, msg, fields...)
+	return New(ctx, codes.FailedPrecondition, msg, fields...).WithSkip(1)
 }
 
 func Unimplemented(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.Unimplemented, msg, fields...)
+	return New(ctx, codes.Unimplemented, msg, fields...).WithSkip(1)
 }
 
 func Unauthenticated(ctx context.Context, msg string, fields ...zap.Field) *Error {
-	return New(ctx, codes.Unauthenticated, msg, fields...)
+	return New(ctx, codes.Unauthenticated, msg, fields...).WithSkip(1)
 }
 
 func RecoverFunc(ctx context.Context, v any) error {
-	return Internal(ctx, string(debug.Stack()), zap.Any("recover", v)).AtPanic()
-}
-
-func ErrorPresenter(logger *zap.Logger) func(context.Context, error) *gqlerror.Error {
-	return func(ctx context.Context, err error) *gqlerror.Error {
-		if err == nil {
-			return nil
-		}
-
-		e := &Error{}
-		if errors.As(err, &e) {
-			logError(logger, e)
-			return e.toGQLError()
-		}
-
-		logger.Error(
-			"received error that was not of type *gqlerr.Error",
-			zap.String("type", fmt.Sprintf("%T", err)),
-			zap.Error(err),
-		)
-		return Internal(ctx, err.Error(), zap.Error(err)).toGQLError()
-	}
+	return Internal(ctx, string(debug.Stack()), zap.Any("recover", v)).WithSkip(1).AtPanic()
 }
 
-func logError(logger *zap.Logger, err *Error) {
+// logError logs err to logger at its resolved level, attaching its caller
+// and (for errorLevel/panicLevel, when captureStack is set) a full
+// stacktrace, so that a triaging engineer doesn't have to reconstruct where
+// in the resolver chain the error came from.
+//
+// The fields added here are only ever passed to logger, never written back
+// onto err.fields: that slice is also what the public Fields() getter and
+// grpcstatus.ToStatus expose to callers, and a stack trace or absolute
+// source path has no business ending up in a gRPC DebugInfo shipped to an
+// external client.
+func logError(logger *zap.Logger, err *Error, captureStack bool) {
+	level := err.logLevel()
+
 	var logFn func(msg string, fields ...zap.Field)
-	switch err.logLevel() {
+	switch level {
 	case debugLevel:
 		logFn = logger.Debug
 	case infoLevel:
@@ -301,9 +370,27 @@ func logError(logger *zap.Logger, err *Error) {
 		logFn = logger.Error
 	}
 
+	fields := make([]zap.Field, len(err.fields), len(err.fields)+4)
+	copy(fields, err.fields)
+
+	if err.cause != nil {
+		fields = append(fields, zap.Error(err.cause))
+	}
+	fields = append(fields, zap.Uint32("full_code", err.code.FullCode()))
+
+	// Prefixed with "gqlerr_" since "caller"/"stacktrace" are the keys zap's
+	// own AddCaller/AddStacktrace use; re-emitting them as fields under the
+	// same name would produce duplicate keys in the encoded log line.
+	if err.caller != "" {
+		fields = append(fields, zap.String("gqlerr_caller", err.caller))
+	}
+	if captureStack && (level == errorLevel || level == panicLevel) {
+		fields = append(fields, zap.Stack("gqlerr_stacktrace"))
+	}
+
 	if path := err.path.String(); path != "" {
-		err.fields = append([]zap.Field{zap.String("gql_path", err.path.String())}, err.fields...)
+		fields = append([]zap.Field{zap.String("gql_path", err.path.String())}, fields...)
 	}
 
-	logFn(err.msg, err.fields...)
+	logFn(err.msg, fields...)
 }
@@ -16,15 +16,45 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 )
 
-func TestErr(t *testing.T) {
+func TestWrap_Unwrap(t *testing.T) {
+	cause := randomError{}
 	gErr := Internal(context.Background(), "some error",
 		zap.String("a_field", "test"),
 		zap.Int("another_field", 123),
-		zap.Error(randomError{}))
+	).Wrap(cause)
 
-	err := gErr.err()
-	if !errors.Is(err, randomError{}) {
-		t.Errorf("returned error was %v, not %v", err, randomError{})
+	if !errors.Is(gErr, cause) {
+		t.Errorf("errors.Is(gErr, %v) = false, want true", cause)
+	}
+
+	var target randomError
+	if !errors.As(gErr, &target) {
+		t.Errorf("errors.As(gErr, &target) = false, want true")
+	}
+
+	if got, want := gErr.Error(), `["internal"] some error: a random error`; got != want {
+		t.Errorf("gErr.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestIs_MatchesByCode(t *testing.T) {
+	ctx := context.Background()
+	gErr := NotFound(ctx, "muffin not found")
+
+	if !errors.Is(gErr, NotFound(ctx, "a different muffin not found")) {
+		t.Error("errors.Is did not match two NotFound errors by code")
+	}
+	if errors.Is(gErr, Internal(ctx, "some other error")) {
+		t.Error("errors.Is matched errors with different codes")
+	}
+}
+
+func TestIs_NilReceiverDoesNotPanic(t *testing.T) {
+	var e *Error
+	var err error = e
+
+	if errors.Is(err, NotFound(context.Background(), "muffin not found")) {
+		t.Error("errors.Is matched a nil *Error against a non-nil one")
 	}
 }
 
@@ -110,13 +140,19 @@ func TestPresenter_ErrorOfWrongType(t *testing.T) {
 	// For a random error, we should turn it into an internal error.
 	wantErr := &gqlerror.Error{
 		Message:    "internal error",
-		Extensions: map[string]interface{}{"code": "internal"},
+		Extensions: map[string]interface{}{"code": "internal", "full_code": uint32(503)},
 	}
 	if diff := cmp.Diff(wantErr, err, errOpts()); diff != "" {
 		t.Errorf("unexpected GQL error returned (-want +got)\n%s", diff)
 	}
 
 	gotLogs := logs.AllUntimed()
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(gotLogs))
+	}
+	assertHasCallerAndStack(t, gotLogs[0].Context)
+	gotLogs[0].Context = withoutFields(gotLogs[0].Context, "gqlerr_caller", "gqlerr_stacktrace")
+
 	wantLogs := []observer.LoggedEntry{
 		{
 			Entry: zapcore.Entry{
@@ -126,6 +162,7 @@ func TestPresenter_ErrorOfWrongType(t *testing.T) {
 			Context: []zapcore.Field{
 				{Key: "type", Type: zapcore.StringType, String: "gqlerr.randomError"},
 				{Key: "error", Type: zapcore.ErrorType, Interface: randomError{}},
+				{Key: "full_code", Type: zapcore.Uint32Type, Integer: 503},
 			},
 		},
 	}
@@ -162,6 +199,7 @@ func TestPresenter(t *testing.T) {
 		Message: "bad input given",
 		Extensions: map[string]interface{}{
 			"code":         "invalid_argument",
+			"full_code":    uint32(101),
 			"error_reason": "muffins_must_be_positive",
 		},
 	}
@@ -170,6 +208,11 @@ func TestPresenter(t *testing.T) {
 	}
 
 	gotLogs := logs.AllUntimed()
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(gotLogs))
+	}
+	assertHasCallerAndStack(t, gotLogs[0].Context)
+
 	wantLogs := []observer.LoggedEntry{
 		{
 			Entry: zapcore.Entry{
@@ -180,15 +223,60 @@ func TestPresenter(t *testing.T) {
 				{Key: "muffin_type", Type: zapcore.StringType, String: "blueberry"},
 				{Key: "muffin_count", Type: zapcore.Int64Type, Integer: -123},
 				{Key: "error", Type: zapcore.ErrorType, Interface: underlyingError},
+				{Key: "full_code", Type: zapcore.Uint32Type, Integer: 101},
 			},
 		},
 	}
+	gotLogs[0].Context = withoutFields(gotLogs[0].Context, "gqlerr_caller", "gqlerr_stacktrace")
 
 	if diff := cmp.Diff(wantLogs, gotLogs, cmpopts.EquateErrors()); diff != "" {
 		t.Errorf("unexpected logs written (-want +got)\n%s", diff)
 	}
 }
 
+// withoutFields returns fields with any field whose key is in exclude
+// removed, for stripping fields (e.g. caller/stacktrace) whose value is
+// inherently unpredictable from an exact-match log assertion.
+func withoutFields(fields []zapcore.Field, exclude ...string) []zapcore.Field {
+	out := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		skip := false
+		for _, key := range exclude {
+			if f.Key == key {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// assertHasCallerAndStack checks that an errorLevel/panicLevel log entry got
+// a non-empty caller and stacktrace field, without asserting their exact
+// (environment-dependent) content.
+func assertHasCallerAndStack(t *testing.T, fields []zapcore.Field) {
+	t.Helper()
+
+	var gotCaller, gotStack bool
+	for _, f := range fields {
+		switch f.Key {
+		case "gqlerr_caller":
+			gotCaller = f.String != ""
+		case "gqlerr_stacktrace":
+			gotStack = true
+		}
+	}
+	if !gotCaller {
+		t.Error("expected a non-empty \"gqlerr_caller\" field")
+	}
+	if !gotStack {
+		t.Error("expected a \"gqlerr_stacktrace\" field")
+	}
+}
+
 func errOpts() cmp.Option {
 	return cmp.Options{
 		cmpopts.IgnoreUnexported(gqlerror.Error{}),
@@ -227,8 +315,14 @@ func TestRecoverFunc(t *testing.T) {
 	)
 
 	err := RecoverFunc(context.Background(), "Panic! At The Disco")
-	logError(logger, err.(*Error))
+	logError(logger, err.(*Error), true /* captureStack */)
 	gotLogs := logs.AllUntimed()
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(gotLogs))
+	}
+	assertHasCallerAndStack(t, gotLogs[0].Context)
+	gotLogs[0].Context = withoutFields(gotLogs[0].Context, "gqlerr_caller", "gqlerr_stacktrace")
+
 	wantLogs := []observer.LoggedEntry{
 		{
 			Entry: zapcore.Entry{
@@ -237,6 +331,7 @@ func TestRecoverFunc(t *testing.T) {
 			},
 			Context: []zapcore.Field{
 				{Key: "recover", Type: zapcore.StringType, String: "Panic! At The Disco"},
+				{Key: "full_code", Type: zapcore.Uint32Type, Integer: 503},
 			},
 		},
 	}
@@ -0,0 +1,138 @@
+// Package grpcstatus converts between *gqlerr.Error and gRPC's status type,
+// for services that expose both a GraphQL API (via gqlerr) and a gRPC API
+// and want the two to report failures consistently.
+package grpcstatus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	gqlerr "github.com/Silicon-Ally/gqlerr"
+	"github.com/Silicon-Ally/gqlerr/codes"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codeToGRPC maps gqlerr's pre-registered codes to the closest equivalent
+// grpc/codes.Code, so ToStatus/FromStatus don't need a mapping hand-rolled
+// per service.
+var codeToGRPC = map[codes.Code]grpccodes.Code{
+	codes.InvalidArgument:    grpccodes.InvalidArgument,
+	codes.NotFound:           grpccodes.NotFound,
+	codes.AlreadyExists:      grpccodes.AlreadyExists,
+	codes.PermissionDenied:   grpccodes.PermissionDenied,
+	codes.ResourceExhausted:  grpccodes.ResourceExhausted,
+	codes.FailedPrecondition: grpccodes.FailedPrecondition,
+	codes.Unimplemented:      grpccodes.Unimplemented,
+	codes.Internal:           grpccodes.Internal,
+	codes.Unauthenticated:    grpccodes.Unauthenticated,
+}
+
+var grpcToCode map[grpccodes.Code]codes.Code
+
+func init() {
+	grpcToCode = make(map[grpccodes.Code]codes.Code, len(codeToGRPC))
+	for code, grpcCode := range codeToGRPC {
+		grpcToCode[grpcCode] = code
+	}
+}
+
+// ToStatus converts a *gqlerr.Error into the equivalent gRPC status. The
+// error's code is mapped to the nearest grpc/codes.Code (falling back to
+// Unknown for codes with no mapping, e.g. ones registered under a custom
+// scope), and its client message becomes the status message. The errorID,
+// path, and fields are attached as ErrorInfo/DebugInfo details, so that a
+// gRPC client or piece of middleware gets the same context a GraphQL
+// response would.
+func ToStatus(e *gqlerr.Error) *status.Status {
+	if e == nil {
+		return nil
+	}
+
+	grpcCode, ok := codeToGRPC[e.Code()]
+	if !ok {
+		grpcCode = grpccodes.Unknown
+	}
+
+	st := status.New(grpcCode, e.ClientMessage())
+	withDetails, err := st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: string(e.ErrorID()),
+			Domain: "gqlerr",
+			Metadata: map[string]string{
+				"code": e.Code().String(),
+			},
+		},
+		&errdetails.DebugInfo{
+			Detail:       e.Path().String(),
+			StackEntries: fieldsToStrings(e.Fields()),
+		},
+	)
+	if err != nil {
+		// The details above are well-known types that always marshal
+		// cleanly, but fall back to the bare status rather than losing the
+		// error entirely if that ever changes.
+		return st
+	}
+	return withDetails
+}
+
+// FromStatus converts a gRPC status into a *gqlerr.Error, for use in a
+// resolver that calls a gRPC backend, e.g.:
+//
+//	resp, err := client.DoThing(ctx, req)
+//	if err != nil {
+//		return nil, grpcstatus.FromStatus(ctx, status.Convert(err))
+//	}
+//
+// The status's code is mapped to the nearest codes.Code (falling back to
+// Internal), and its message becomes the error's log message. The original
+// status is preserved as the error's cause via Wrap, so errors.Is/errors.As
+// still reach it.
+func FromStatus(ctx context.Context, st *status.Status) *gqlerr.Error {
+	if st == nil {
+		return nil
+	}
+
+	code, ok := grpcToCode[st.Code()]
+	if !ok {
+		code = codes.Internal
+	}
+
+	var errID gqlerr.ErrorID
+	fields := []zap.Field{zap.String("grpc_code", st.Code().String())}
+	for _, d := range st.Details() {
+		switch detail := d.(type) {
+		case *errdetails.ErrorInfo:
+			errID = gqlerr.ErrorID(detail.GetReason())
+		case *errdetails.DebugInfo:
+			fields = append(fields, zap.Strings("grpc_stack_entries", detail.GetStackEntries()))
+		}
+	}
+
+	e := gqlerr.New(ctx, code, st.Message(), fields...).Wrap(st.Err())
+	if errID != "" {
+		e = e.WithErrorID(errID)
+	}
+	return e
+}
+
+// fieldsToStrings renders zap fields as sorted "key=value" strings, for
+// attaching to a DebugInfo detail.
+func fieldsToStrings(fields []zap.Field) []string {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	entries := make([]string, 0, len(enc.Fields))
+	for k, v := range enc.Fields {
+		entries = append(entries, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(entries)
+	return entries
+}
@@ -0,0 +1,78 @@
+package grpcstatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	gqlerr "github.com/Silicon-Ally/gqlerr"
+	"github.com/Silicon-Ally/gqlerr/codes"
+	"go.uber.org/zap/zaptest"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	e := gqlerr.NotFound(context.Background(), "muffin not found").
+		WithMessage("no muffin with that ID").
+		WithErrorID("muffin_not_found")
+
+	st := ToStatus(e)
+
+	if got, want := st.Code(), grpccodes.NotFound; got != want {
+		t.Errorf("st.Code() = %v, want %v", got, want)
+	}
+	if got, want := st.Message(), "no muffin with that ID"; got != want {
+		t.Errorf("st.Message() = %q, want %q", got, want)
+	}
+}
+
+func TestToStatus_UnmappedCode(t *testing.T) {
+	e := gqlerr.New(context.Background(), codes.NewCode(codes.Scope(1), codes.CategoryPubsub, 1), "whoops")
+
+	if got, want := ToStatus(e).Code(), grpccodes.Unknown; got != want {
+		t.Errorf("st.Code() = %v, want %v", got, want)
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	st := status.New(grpccodes.AlreadyExists, "muffin already exists")
+
+	e := FromStatus(context.Background(), st)
+
+	if got, want := e.Code(), codes.AlreadyExists; got != want {
+		t.Errorf("e.Code() = %v, want %v", got, want)
+	}
+	if !errors.Is(e, st.Err()) {
+		t.Error("errors.Is(e, st.Err()) = false, want true, the status should be preserved as the cause")
+	}
+}
+
+func TestToStatus_DoesNotLeakLoggedFields(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	e := gqlerr.Internal(context.Background(), "db connection failed")
+
+	// Logging e (as a real presenter would before converting it to a status)
+	// must not attach internal-only fields like a stack trace or source file
+	// path to the *Error itself, since ToStatus forwards Fields() verbatim
+	// into the gRPC status's DebugInfo, which can reach an external client.
+	gqlerr.ErrorPresenter(logger)(context.Background(), e)
+
+	ToStatus(e)
+	for _, f := range e.Fields() {
+		if strings.Contains(f.Key, "caller") || strings.Contains(f.Key, "stacktrace") {
+			t.Errorf("e.Fields() contains internal field %q after logging, want it untouched", f.Key)
+		}
+	}
+}
+
+func TestFromStatus_UnmappedCode(t *testing.T) {
+	st := status.New(grpccodes.DataLoss, "disk caught fire")
+
+	e := FromStatus(context.Background(), st)
+
+	if got, want := e.Code(), codes.Internal; got != want {
+		t.Errorf("e.Code() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package gqlerr
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// MultiError aggregates multiple *Error values into a single error, for
+// resolvers that can fail in more than one place at once, e.g. a batch
+// mutation that partially succeeds. The GraphQL spec allows an array of
+// errors per response, and a *MultiError returned from a resolver is
+// recognized by a presenter built with NewPresenter/ErrorPresenter, which
+// logs each contained *Error individually and reports the full list to the
+// client, preserving each one's own path, errorID, and client message.
+type MultiError struct {
+	errs []*Error
+}
+
+// Multi creates a *MultiError aggregating errs.
+func Multi(errs ...*Error) *MultiError {
+	return &MultiError{errs: errs}
+}
+
+// Append adds other to e's errors, creating a *MultiError. It's meant for
+// ergonomic accumulation inside a resolver that validates multiple fields,
+// e.g. `errs := firstErr.Append(secondErr).Append(thirdErr)`.
+func (e *Error) Append(other *Error) *MultiError {
+	return &MultiError{errs: []*Error{e, other}}
+}
+
+// Append adds other to m's errors, and returns m for chaining.
+func (m *MultiError) Append(other *Error) *MultiError {
+	m.errs = append(m.errs, other)
+	return m
+}
+
+// Errors returns the errors aggregated in m.
+func (m *MultiError) Errors() []*Error {
+	return m.errs
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.errs))
+	for i, e := range m.errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidationError describes a single field that failed validation, for use
+// with FromValidationErrors.
+type ValidationError struct {
+	// Field is the name of the field that failed validation.
+	Field string
+	// Reason is a client-facing description of why the field was invalid.
+	Reason string
+}
+
+// FromValidationErrors builds a single InvalidArgument *MultiError from a
+// slice of per-field validation failures, e.g. from validating the inputs of
+// a batch mutation. Each resulting error gets an ast.Path of just that
+// field's name and Reason as its client message.
+func FromValidationErrors(ctx context.Context, errs []ValidationError) *MultiError {
+	m := &MultiError{errs: make([]*Error, len(errs))}
+	for i, ve := range errs {
+		e := InvalidArgument(ctx, ve.Reason).WithMessage(ve.Reason)
+		e.path = ast.Path{ast.PathName(ve.Field)}
+		m.errs[i] = e
+	}
+	return m
+}
@@ -0,0 +1,77 @@
+package gqlerr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMultiError_Append(t *testing.T) {
+	ctx := context.Background()
+	first := NotFound(ctx, "muffin not found")
+	second := InvalidArgument(ctx, "bad muffin count")
+
+	m := first.Append(second).Append(InvalidArgument(ctx, "bad muffin flavor"))
+
+	if got, want := len(m.Errors()), 3; got != want {
+		t.Fatalf("len(m.Errors()) = %d, want %d", got, want)
+	}
+}
+
+func TestFromValidationErrors(t *testing.T) {
+	ctx := context.Background()
+	m := FromValidationErrors(ctx, []ValidationError{
+		{Field: "count", Reason: "must be positive"},
+		{Field: "flavor", Reason: "unknown flavor"},
+	})
+
+	errs := m.Errors()
+	if got, want := len(errs), 2; got != want {
+		t.Fatalf("len(errs) = %d, want %d", got, want)
+	}
+	for i, want := range []string{"count", "flavor"} {
+		if got := errs[i].Path().String(); got != want {
+			t.Errorf("errs[%d].Path() = %q, want %q", i, got, want)
+		}
+		if got, wantMsg := errs[i].ClientMessage(), []string{"must be positive", "unknown flavor"}[i]; got != wantMsg {
+			t.Errorf("errs[%d].ClientMessage() = %q, want %q", i, got, wantMsg)
+		}
+		if got := errs[i].Code(); got != InvalidArgument(ctx, "").Code() {
+			t.Errorf("errs[%d].Code() = %v, want InvalidArgument", i, got)
+		}
+	}
+}
+
+func TestPresenter_MultiError(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	present := NewPresenter(WithLogger(logger))
+
+	ctx := graphql.WithResponseContext(context.Background(),
+		func(ctx context.Context, err error) *gqlerror.Error { return present(ctx, err) },
+		func(ctx context.Context, err interface{}) error { return nil },
+	)
+
+	handlerErr := Multi(
+		InvalidArgument(ctx, "bad count").WithMessage("count must be positive"),
+		InvalidArgument(ctx, "bad flavor").WithMessage("unknown flavor"),
+	)
+
+	last := present(ctx, handlerErr)
+	if got, want := last.Message, "unknown flavor"; got != want {
+		t.Errorf("last.Message = %q, want %q", got, want)
+	}
+
+	// The first error should've been added to the response directly via
+	// graphql.AddError, since it's not the one this presenter call returns.
+	gotErrs := graphql.GetErrors(ctx)
+	wantErrs := gqlerror.List{
+		{Message: "count must be positive", Extensions: map[string]interface{}{"code": "invalid_argument", "full_code": uint32(101)}},
+	}
+	if diff := cmp.Diff(wantErrs, gotErrs, errOpts()); diff != "" {
+		t.Errorf("unexpected errors in response (-want +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,230 @@
+package gqlerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Silicon-Ally/gqlerr/codes"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+)
+
+// Level is a logging severity, mirroring the levels available in
+// zapcore.Level, used to override the default level for a code via
+// WithLevelForCode.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelPanic
+)
+
+func (l Level) logLevel() logLevel {
+	switch l {
+	case LevelDebug:
+		return debugLevel
+	case LevelInfo:
+		return infoLevel
+	case LevelWarn:
+		return warnLevel
+	case LevelError:
+		return errorLevel
+	case LevelPanic:
+		return panicLevel
+	default:
+		return unsetLevel
+	}
+}
+
+// presenterConfig holds everything NewPresenter's options can customize
+// about how errors are logged and translated into a *gqlerror.Error.
+type presenterConfig struct {
+	logger           *zap.Logger
+	levelOverrides   map[codes.Code]logLevel
+	messageOverrides map[codes.Code]string
+	fieldExtractor   func(context.Context) []zap.Field
+	sanitizer        func(*Error) *Error
+	fallback         func(context.Context, error) *Error
+	captureStack     bool
+	sampler          *sampler
+}
+
+// Option customizes the presenter returned by NewPresenter.
+type Option func(*presenterConfig)
+
+// WithLogger sets the logger errors are written to. Without it, a presenter
+// built by NewPresenter won't log anything, it'll only translate errors into
+// their *gqlerror.Error form.
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *presenterConfig) {
+		c.logger = logger
+	}
+}
+
+// WithLevelForCode overrides the log level used for errors of the given
+// code, taking precedence over both this package's defaults and a level set
+// via (*Error).AtDebug/AtInfo/etc. It also works for codes this package has
+// no built-in default for, e.g. ones registered under a custom scope.
+func WithLevelForCode(code codes.Code, level Level) Option {
+	return func(c *presenterConfig) {
+		c.levelOverrides[code] = level.logLevel()
+	}
+}
+
+// WithDefaultMessage overrides the client-facing message used for errors of
+// the given code that don't already have one set via (*Error).WithMessage.
+// Like WithLevelForCode, it also works for codes this package has no
+// built-in default for.
+func WithDefaultMessage(code codes.Code, msg string) Option {
+	return func(c *presenterConfig) {
+		c.messageOverrides[code] = msg
+	}
+}
+
+// WithFieldExtractor adds fields from ctx to every error logged by the
+// presenter, e.g. a request ID or user ID threaded through the context.
+func WithFieldExtractor(extractor func(context.Context) []zap.Field) Option {
+	return func(c *presenterConfig) {
+		c.fieldExtractor = extractor
+	}
+}
+
+// WithSanitizer runs every error through sanitizer immediately before it's
+// turned into a *gqlerror.Error, after it's been logged. It's meant for
+// stripping internal details (e.g. a default message that leaked a
+// stack trace) before the error reaches the client in production.
+func WithSanitizer(sanitizer func(*Error) *Error) Option {
+	return func(c *presenterConfig) {
+		c.sanitizer = sanitizer
+	}
+}
+
+// WithStackCapture turns the "gqlerr_stacktrace" field logged for errorLevel
+// and panicLevel errors on or off. It's on by default; disable it in
+// perf-sensitive deployments where capturing a full stacktrace on every
+// error is too expensive.
+func WithStackCapture(enabled bool) Option {
+	return func(c *presenterConfig) {
+		c.captureStack = enabled
+	}
+}
+
+// WithSampler throttles how many logs are written for errors sharing the
+// same key (by default, code + ErrorID), per cfg.Tick window, to keep a
+// flood of identical errors (a misbehaving client, a downstream outage) from
+// blowing up log volume and paging systems. Errors dropped by the sampler are
+// counted per key and reported in one aggregate log entry when the next error
+// with that key arrives after its window has ended; there is no background
+// flush, so drops from a key's last window are not reported unless that key
+// occurs again. It only affects what's logged; the GraphQL response sent to
+// the client is never sampled.
+func WithSampler(cfg SamplerConfig) Option {
+	return func(c *presenterConfig) {
+		c.sampler = newSampler(cfg)
+	}
+}
+
+// WithFallback customizes how errors that aren't a *gqlerr.Error (i.e.
+// errors.As fails) are turned into one. Without it, such errors are logged
+// as unexpected and wrapped as an Internal error with the same behavior as
+// ErrorPresenter.
+func WithFallback(fallback func(context.Context, error) *Error) Option {
+	return func(c *presenterConfig) {
+		c.fallback = fallback
+	}
+}
+
+// NewPresenter builds a gqlgen-compatible error presenter from the given
+// options. Unlike the fixed defaults baked into ErrorPresenter, it lets
+// callers override log levels/messages per code, register brand-new codes
+// with their own defaults, inject context-derived fields, sanitize errors
+// before they reach the client, and customize how non-*gqlerr.Error values
+// are handled.
+func NewPresenter(opts ...Option) func(context.Context, error) *gqlerror.Error {
+	cfg := &presenterConfig{
+		levelOverrides:   map[codes.Code]logLevel{},
+		messageOverrides: map[codes.Code]string{},
+		captureStack:     true,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		if err == nil {
+			return nil
+		}
+
+		me := &MultiError{}
+		if errors.As(err, &me) {
+			return cfg.presentMulti(ctx, me)
+		}
+
+		e := &Error{}
+		if !errors.As(err, &e) {
+			if cfg.fallback != nil {
+				e = cfg.fallback(ctx, err)
+			} else {
+				e = Internal(ctx, "received error that was not of type *gqlerr.Error",
+					zap.String("type", fmt.Sprintf("%T", err)),
+					zap.Error(err),
+				)
+			}
+		}
+
+		return cfg.prepare(ctx, e).toGQLError()
+	}
+}
+
+// prepare applies the presenter's overrides/field extractor to e, logs it,
+// and runs it through the sanitizer, returning the resulting *Error.
+func (cfg *presenterConfig) prepare(ctx context.Context, e *Error) *Error {
+	if lvl, ok := cfg.levelOverrides[e.code]; ok {
+		e.level = lvl
+	}
+	if msg, ok := cfg.messageOverrides[e.code]; ok && e.clientMsg == "" {
+		e.clientMsg = msg
+	}
+	if cfg.fieldExtractor != nil {
+		e.fields = append(e.fields, cfg.fieldExtractor(ctx)...)
+	}
+
+	if cfg.logger != nil && (cfg.sampler == nil || cfg.sampler.allow(e, cfg.logger)) {
+		logError(cfg.logger, e, cfg.captureStack)
+	}
+
+	if cfg.sanitizer != nil {
+		e = cfg.sanitizer(e)
+	}
+
+	return e
+}
+
+// presentMulti reports every error in me individually, so each keeps its own
+// level/fields/path/errorID/client message, and the client sees the full,
+// concatenated list. All but the last are sent via graphql.AddError, which
+// runs them back through this same presenter; the last is presented and
+// returned directly, since it's this resolver's own presented error.
+func (cfg *presenterConfig) presentMulti(ctx context.Context, me *MultiError) *gqlerror.Error {
+	if len(me.errs) == 0 {
+		return nil
+	}
+
+	for _, e := range me.errs[:len(me.errs)-1] {
+		graphql.AddError(ctx, e)
+	}
+	return cfg.prepare(ctx, me.errs[len(me.errs)-1]).toGQLError()
+}
+
+// ErrorPresenter returns a gqlgen-compatible error presenter that logs every
+// error to logger, using this package's default level/message per code. It's
+// a thin wrapper around NewPresenter for callers that don't need any of its
+// other options.
+func ErrorPresenter(logger *zap.Logger) func(context.Context, error) *gqlerror.Error {
+	return NewPresenter(WithLogger(logger))
+}
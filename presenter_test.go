@@ -0,0 +1,160 @@
+package gqlerr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Silicon-Ally/gqlerr/codes"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewPresenter_WithLevelForCode(t *testing.T) {
+	core, logs := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	present := NewPresenter(
+		WithLogger(logger),
+		// NotFound is normally logged at warn, downgrade it to debug for this
+		// service since it's extremely common and not actionable.
+		WithLevelForCode(codes.NotFound, LevelDebug),
+	)
+
+	present(context.Background(), NotFound(context.Background(), "muffin not found"))
+
+	gotLogs := logs.AllUntimed()
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(gotLogs))
+	}
+	if got, want := gotLogs[0].Entry.Level, zapcore.DebugLevel; got != want {
+		t.Errorf("log level = %v, want %v", got, want)
+	}
+}
+
+func TestNewPresenter_WithDefaultMessage(t *testing.T) {
+	present := NewPresenter(WithDefaultMessage(codes.NotFound, "couldn't find that"))
+
+	got := present(context.Background(), NotFound(context.Background(), "muffin not found"))
+	if want := "couldn't find that"; got.Message != want {
+		t.Errorf("Message = %q, want %q", got.Message, want)
+	}
+}
+
+func TestNewPresenter_WithDefaultMessage_DoesNotOverrideExplicitMessage(t *testing.T) {
+	present := NewPresenter(WithDefaultMessage(codes.NotFound, "couldn't find that"))
+
+	handlerErr := NotFound(context.Background(), "muffin not found").WithMessage("no such muffin")
+	got := present(context.Background(), handlerErr)
+	if want := "no such muffin"; got.Message != want {
+		t.Errorf("Message = %q, want %q", got.Message, want)
+	}
+}
+
+func TestNewPresenter_WithFieldExtractor(t *testing.T) {
+	core, logs := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	present := NewPresenter(
+		WithLogger(logger),
+		WithFieldExtractor(func(ctx context.Context) []zap.Field {
+			return []zap.Field{zap.String("request_id", "req-123")}
+		}),
+	)
+
+	present(context.Background(), NotFound(context.Background(), "muffin not found"))
+
+	gotLogs := logs.AllUntimed()
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(gotLogs))
+	}
+
+	found := false
+	for _, f := range gotLogs[0].Context {
+		if f.Key == "request_id" && f.String == "req-123" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("extracted request_id field not found in logged fields")
+	}
+}
+
+func TestNewPresenter_WithSanitizer(t *testing.T) {
+	present := NewPresenter(
+		WithSanitizer(func(e *Error) *Error {
+			return e.WithMessage("an error occurred")
+		}),
+	)
+
+	got := present(context.Background(), Internal(context.Background(), "db connection string: postgres://..."))
+	if want := "an error occurred"; got.Message != want {
+		t.Errorf("Message = %q, want %q", got.Message, want)
+	}
+}
+
+func TestNewPresenter_WithFallback(t *testing.T) {
+	present := NewPresenter(
+		WithFallback(func(ctx context.Context, err error) *Error {
+			return Unauthenticated(ctx, err.Error()).WithMessage("please log in")
+		}),
+	)
+
+	got := present(context.Background(), randomError{})
+	if want := "please log in"; got.Message != want {
+		t.Errorf("Message = %q, want %q", got.Message, want)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"code": "unauthenticated", "full_code": uint32(402)}, got.Extensions); diff != "" {
+		t.Errorf("unexpected extensions (-want +got)\n%s", diff)
+	}
+}
+
+func TestNewPresenter_ErrorOfWrongTypeGetsExtractedFieldsAndSampling(t *testing.T) {
+	core, logs := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	present := NewPresenter(
+		WithLogger(logger),
+		WithFieldExtractor(func(ctx context.Context) []zap.Field {
+			return []zap.Field{zap.String("request_id", "req-123")}
+		}),
+		WithSampler(SamplerConfig{First: 1, Thereafter: 100}),
+	)
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		present(ctx, randomError{})
+	}
+
+	gotLogs := logs.AllUntimed()
+	// Only the first should've been logged; the rest are sampled out, same as
+	// for a *gqlerr.Error, since this path now goes through cfg.prepare too.
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1 (the rest sampled out)", len(gotLogs))
+	}
+
+	found := false
+	for _, f := range gotLogs[0].Context {
+		if f.Key == "request_id" && f.String == "req-123" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("extracted request_id field not found in logged fields for a non-*gqlerr.Error")
+	}
+}
+
+func TestErrorPresenter_MatchesNewPresenterDefaults(t *testing.T) {
+	core, logs := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	got := ErrorPresenter(logger)(context.Background(), NotFound(context.Background(), "muffin not found"))
+	if want := "not found"; got.Message != want {
+		t.Errorf("Message = %q, want %q", got.Message, want)
+	}
+	if n := len(logs.AllUntimed()); n != 1 {
+		t.Errorf("got %d logs, want 1", n)
+	}
+}
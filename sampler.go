@@ -0,0 +1,117 @@
+package gqlerr
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SamplerConfig configures the log sampling enabled by WithSampler, modeled
+// after zapcore.NewSamplerWithOptions: within each Tick window, the first
+// First occurrences of a given key are logged, then every Thereafter-th one,
+// with the rest counted and reported in a single aggregate entry by the next
+// occurrence of that key after the window has ended.
+type SamplerConfig struct {
+	// Tick is the window over which sampling counts reset. Defaults to 1s.
+	Tick time.Duration
+	// First is the number of occurrences of a given key to always log within
+	// a Tick window. Defaults to 10.
+	First int
+	// Thereafter is the sampling rate applied once First is exceeded within a
+	// Tick window, e.g. 100 logs every 100th occurrence. Defaults to 100.
+	Thereafter int
+	// KeyFunc computes the sampling key for an error. Errors with the same
+	// key share a sample budget. Defaults to combining the error's code and
+	// ErrorID.
+	KeyFunc func(e *Error) string
+}
+
+// defaultSampleKey keys on code and ErrorID, since those are what typically
+// identify a flood of otherwise-distinct errors, e.g. a client retrying a bad
+// request or a downstream dependency failing the same way repeatedly.
+func defaultSampleKey(e *Error) string {
+	return e.code.String() + "|" + string(e.errorID)
+}
+
+// sampleCounter tracks the current Tick window's counts for a single
+// sampling key.
+type sampleCounter struct {
+	mu        sync.Mutex
+	windowEnd time.Time
+	count     int
+	dropped   int
+}
+
+// sampler implements the sampling/de-duplication behavior configured by
+// SamplerConfig, built by WithSampler.
+type sampler struct {
+	cfg SamplerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	counters map[string]*sampleCounter
+}
+
+func newSampler(cfg SamplerConfig) *sampler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = time.Second
+	}
+	if cfg.First <= 0 {
+		cfg.First = 10
+	}
+	if cfg.Thereafter <= 0 {
+		cfg.Thereafter = 100
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = defaultSampleKey
+	}
+	return &sampler{
+		cfg:      cfg,
+		now:      time.Now,
+		counters: map[string]*sampleCounter{},
+	}
+}
+
+// allow reports whether e should be logged, given the sampling budget for its
+// key in the current window. If the previous window ended with errors
+// dropped for this key, it's flushed to logger as a single aggregate entry
+// before the new window starts.
+func (s *sampler) allow(e *Error, logger *zap.Logger) bool {
+	key := s.cfg.KeyFunc(e)
+
+	s.mu.Lock()
+	c, ok := s.counters[key]
+	if !ok {
+		c = &sampleCounter{}
+		s.counters[key] = c
+	}
+	s.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := s.now()
+	if now.After(c.windowEnd) {
+		if c.dropped > 0 && logger != nil {
+			logger.Warn("dropped sampled errors",
+				zap.Int("dropped", c.dropped),
+				zap.String("code", e.code.String()),
+				zap.String("error_reason", string(e.errorID)),
+			)
+		}
+		c.windowEnd = now.Add(s.cfg.Tick)
+		c.count = 0
+		c.dropped = 0
+	}
+
+	c.count++
+	if c.count <= s.cfg.First {
+		return true
+	}
+	if (c.count-s.cfg.First)%s.cfg.Thereafter == 0 {
+		return true
+	}
+	c.dropped++
+	return false
+}
@@ -0,0 +1,122 @@
+package gqlerr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSampler_FirstNThenEveryMth(t *testing.T) {
+	s := newSampler(SamplerConfig{First: 2, Thereafter: 3})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	core, _ := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	e := NotFound(context.Background(), "muffin not found")
+
+	// First 2 are always allowed.
+	for i := 0; i < 2; i++ {
+		if !s.allow(e, logger) {
+			t.Errorf("call %d: allow() = false, want true (within First)", i)
+		}
+	}
+	// Next 2 (3rd and 4th occurrence) should be dropped, since Thereafter=3
+	// means every 3rd occurrence past First is let through.
+	for i := 0; i < 2; i++ {
+		if s.allow(e, logger) {
+			t.Errorf("call %d: allow() = true, want false (between samples)", i)
+		}
+	}
+	// 5th occurrence overall is the 3rd past First, so it's let through.
+	if !s.allow(e, logger) {
+		t.Error("3rd occurrence past First: allow() = false, want true")
+	}
+}
+
+func TestSampler_DifferentKeysHaveIndependentBudgets(t *testing.T) {
+	s := newSampler(SamplerConfig{First: 1, Thereafter: 100})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	core, _ := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	ctx := context.Background()
+	notFound := NotFound(ctx, "muffin not found")
+	invalid := InvalidArgument(ctx, "bad muffin count")
+
+	if !s.allow(notFound, logger) {
+		t.Error("first NotFound: allow() = false, want true")
+	}
+	if !s.allow(invalid, logger) {
+		t.Error("first InvalidArgument: allow() = false, want true, since it has a separate budget")
+	}
+	if s.allow(notFound, logger) {
+		t.Error("second NotFound: allow() = true, want false, past First")
+	}
+}
+
+func TestSampler_ReportsDroppedCountOnWindowRollover(t *testing.T) {
+	s := newSampler(SamplerConfig{First: 1, Thereafter: 100})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	core, logs := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	e := NotFound(context.Background(), "muffin not found")
+
+	s.allow(e, logger)           // allowed, within First.
+	s.allow(e, logger)           // dropped.
+	s.allow(e, logger)           // dropped.
+
+	if n := len(logs.All()); n > 0 {
+		t.Fatalf("got %d logs before window rollover, want 0", n)
+	}
+
+	// Roll over to the next window; the 2 dropped occurrences should be
+	// reported as a single aggregate entry.
+	s.now = func() time.Time { return now.Add(2 * time.Second) }
+	s.allow(e, logger)
+
+	gotLogs := logs.AllUntimed()
+	if len(gotLogs) != 1 {
+		t.Fatalf("got %d logs, want 1 aggregate log entry", len(gotLogs))
+	}
+	if got, want := gotLogs[0].Message, "dropped sampled errors"; got != want {
+		t.Errorf("log message = %q, want %q", got, want)
+	}
+	for _, f := range gotLogs[0].Context {
+		if f.Key == "dropped" && f.Integer != 2 {
+			t.Errorf("dropped field = %d, want 2", f.Integer)
+		}
+	}
+}
+
+func TestNewPresenter_WithSampler(t *testing.T) {
+	core, logs := observer.New(zap.LevelEnablerFunc(func(_ zapcore.Level) bool { return true }))
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })))
+
+	present := NewPresenter(WithLogger(logger), WithSampler(SamplerConfig{First: 1, Thereafter: 100}))
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		got := present(ctx, NotFound(ctx, "muffin not found"))
+		// The GraphQL response is never sampled, every call should still
+		// return a populated error.
+		if got == nil {
+			t.Fatalf("call %d: present() = nil, want a *gqlerror.Error", i)
+		}
+	}
+
+	if n := len(logs.All()); n != 1 {
+		t.Errorf("got %d logs, want 1 (the rest sampled out)", n)
+	}
+}